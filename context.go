@@ -0,0 +1,165 @@
+package tbot
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sfardiansyah/tbot/internal/adapter"
+	"github.com/sfardiansyah/tbot/model"
+)
+
+// Context carries everything a handler needs about a single Update: the
+// raw Update, convenience accessors for the Message/Sender/Chat, the Bot
+// used to reply, and a data bag for passing values between middlewares and
+// the final handler.
+type Context interface {
+	// Update returns the Update that triggered this Context.
+	Update() *model.Update
+	// Message returns the Message wrapping the Update.
+	Message() *Message
+	// Sender returns the user who triggered the Update.
+	Sender() *model.User
+	// Chat returns the chat the Update belongs to.
+	Chat() *model.Chat
+	// Bot returns the adapter used to talk back to Telegram.
+	Bot() adapter.BotAdapter
+
+	// Send sends text to the Context's chat.
+	Send(text string) error
+	// Reply sends text to the chat of the Update that triggered this
+	// Context, the same as Send; it is not threaded as a reply.
+	Reply(text string) error
+	// Respond answers the originating callback query, if any.
+	Respond(text string) error
+	// Answer answers the originating inline query with results.
+	Answer(results []InlineQueryResult) error
+
+	// Get returns a value previously stored with Set, or nil.
+	Get(key string) interface{}
+	// Set stores a value scoped to this Context.
+	Set(key string, value interface{})
+}
+
+// ctx is the default Context implementation, built around the existing
+// Message wrapper so legacy *Message handlers keep working during the
+// transition to Context-based ones.
+type ctx struct {
+	message  *Message
+	bot      adapter.BotAdapter
+	data     map[string]interface{}
+	answered bool
+}
+
+// InlineQueryResult is a single item returned from an inline query answer.
+// It mirrors Telegram's inline query result union; only the fields that
+// apply to Type need to be set.
+type InlineQueryResult struct {
+	Type        string `json:"type"`
+	ID          string `json:"id"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+	ThumbURL    string `json:"thumb_url,omitempty"`
+	MessageText string `json:"message_text,omitempty"`
+}
+
+func newContext(bot adapter.BotAdapter, m *Message) Context {
+	return &ctx{message: m, bot: bot, data: make(map[string]interface{})}
+}
+
+func (c *ctx) Update() *model.Update { return &c.message.Message }
+
+func (c *ctx) Message() *Message { return c.message }
+
+// Sender resolves the user who triggered the Update: the top-level sender
+// for a plain message, or the sender of whichever sub-update (callback
+// query, inline query, edited message, channel post, chat member update)
+// actually carries one.
+func (c *ctx) Sender() *model.User {
+	u := &c.message.Message
+	switch {
+	case u.From != nil:
+		return u.From
+	case u.CallbackQuery != nil:
+		return u.CallbackQuery.From
+	case u.InlineQuery != nil:
+		return u.InlineQuery.From
+	case u.EditedMessage != nil:
+		return u.EditedMessage.From
+	case u.ChannelPost != nil:
+		return u.ChannelPost.From
+	case u.ChatMember != nil:
+		return u.ChatMember.From
+	case u.MyChatMember != nil:
+		return u.MyChatMember.From
+	}
+	return nil
+}
+
+// Chat resolves the chat the Update belongs to, the same way Sender
+// resolves the user. An inline query carries no chat, so Chat returns nil
+// for it.
+func (c *ctx) Chat() *model.Chat {
+	u := &c.message.Message
+	switch {
+	case u.Chat != nil:
+		return u.Chat
+	case u.CallbackQuery != nil:
+		return u.CallbackQuery.Chat
+	case u.EditedMessage != nil:
+		return u.EditedMessage.Chat
+	case u.ChannelPost != nil:
+		return u.ChannelPost.Chat
+	case u.ChatMember != nil:
+		return u.ChatMember.Chat
+	case u.MyChatMember != nil:
+		return u.MyChatMember.Chat
+	}
+	return nil
+}
+
+func (c *ctx) Bot() adapter.BotAdapter { return c.bot }
+
+func (c *ctx) Send(text string) error {
+	chat := c.Chat()
+	if chat == nil {
+		return fmt.Errorf("tbot: no chat available in this Context")
+	}
+	return c.bot.Send(&model.Message{Type: model.MessageText, ChatID: chat.ID, Data: text})
+}
+
+// Reply sends text to the chat resolved by Chat, exactly as Send does, for
+// every update kind; the text is not sent as an in-thread reply to the
+// triggering message.
+func (c *ctx) Reply(text string) error {
+	return c.Send(text)
+}
+
+func (c *ctx) Respond(text string) error {
+	if c.message.Message.CallbackQuery == nil {
+		return fmt.Errorf("tbot: Respond called outside a callback query Context")
+	}
+	c.answered = true
+	return c.bot.SendRaw("answerCallbackQuery", map[string]string{
+		"callback_query_id": c.message.Message.CallbackQuery.ID,
+		"text":              text,
+	})
+}
+
+func (c *ctx) Answer(results []InlineQueryResult) error {
+	if c.message.Message.InlineQuery == nil {
+		return fmt.Errorf("tbot: Answer called outside an inline query Context")
+	}
+	encoded, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("tbot: encode inline query results: %w", err)
+	}
+	return c.bot.SendRaw("answerInlineQuery", map[string]string{
+		"inline_query_id": c.message.Message.InlineQuery.ID,
+		"results":         string(encoded),
+	})
+}
+
+func (c *ctx) Get(key string) interface{} { return c.data[key] }
+
+func (c *ctx) Set(key string, value interface{}) { c.data[key] = value }
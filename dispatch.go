@@ -0,0 +1,39 @@
+package tbot
+
+// processMessage routes a single incoming Update to the handler that
+// matches its kind: callback queries, inline queries, edited messages,
+// channel posts, and chat member updates go through the Context-based
+// routes registered via HandleCallback/HandleInlineQuery/HandleEdited/
+// HandleChannelPost/HandleChatMember; anything else is a plain message and
+// falls through to the Mux, which owns command routing, wrapped in the
+// Server's middlewares (added with AddMiddleware), outermost first. Those
+// middlewares apply to plain messages only.
+func (s *Server) processMessage(m *Message) {
+	m.bot = s.bot
+	update := m.Message
+
+	switch {
+	case update.CallbackQuery != nil:
+		c := newContext(s.bot, m).(*ctx)
+		s.callHandler(s.matchCallback(update.CallbackQuery.Data), c)
+		if !c.answered {
+			_ = s.bot.SendRaw("answerCallbackQuery", map[string]string{
+				"callback_query_id": update.CallbackQuery.ID,
+			})
+		}
+	case update.InlineQuery != nil:
+		s.callHandler(s.inlineQueryHandler, newContext(s.bot, m))
+	case update.EditedMessage != nil:
+		s.callHandler(s.editedHandler, newContext(s.bot, m))
+	case update.ChannelPost != nil:
+		s.callHandler(s.channelPostHandler, newContext(s.bot, m))
+	case update.ChatMember != nil, update.MyChatMember != nil:
+		s.callHandler(s.chatMemberHandler, newContext(s.bot, m))
+	default:
+		var handler HandlerFunction = s.mux.ServeMessage
+		for i := len(s.middlewares) - 1; i >= 0; i-- {
+			handler = s.middlewares[i](handler)
+		}
+		handler(m)
+	}
+}
@@ -0,0 +1,49 @@
+package tbot
+
+import (
+	"fmt"
+	"log"
+)
+
+// defaultOnError is the OnError used when the caller doesn't supply one via
+// WithOnError: it logs the error and, if the originating chat is known,
+// replies with a generic error message.
+func defaultOnError(err error, c Context) {
+	log.Printf("tbot: handler error: %v", err)
+	if c != nil && c.Chat() != nil {
+		_ = c.Send("Sorry, something went wrong.")
+	}
+}
+
+// WithOnError sets a hook invoked whenever a handler returns an error or
+// panics. The default logs the error and replies to the originating chat
+// with a generic message; set your own to send errors to Sentry, drop the
+// reply, etc.
+func WithOnError(f func(err error, c Context)) ServerOption {
+	return func(s *Server) {
+		s.onError = f
+	}
+}
+
+// callHandler invokes handler with c, routing any returned error through
+// the Server's OnError hook.
+func (s *Server) callHandler(handler ContextHandlerFunction, c Context) {
+	if handler == nil {
+		return
+	}
+	if err := handler(c); err != nil {
+		s.onError(err, c)
+	}
+}
+
+// safeProcessMessage wraps processMessage with panic recovery, so a single
+// bad update can't take down the whole Server; the recovered value is
+// routed through OnError like any other handler error.
+func (s *Server) safeProcessMessage(m *Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.onError(fmt.Errorf("panic: %v", r), newContext(s.bot, m))
+		}
+	}()
+	s.processMessage(m)
+}
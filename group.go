@@ -0,0 +1,51 @@
+package tbot
+
+// Group is a set of routes registered under a shared middleware stack.
+// Groups let callers compose auth, rate-limiting, or logging around a
+// subset of handlers without mutating Server.middlewares globally, so
+// HandleFunc/Handle/HandleFile keep working unchanged outside the group.
+type Group struct {
+	server      *Server
+	middlewares []Middleware
+}
+
+// Group returns a new Group sharing the Server's Mux, with middlewares
+// applied to every handler registered through it.
+func (s *Server) Group(middlewares ...Middleware) *Group {
+	return &Group{server: s, middlewares: middlewares}
+}
+
+// Group returns a child Group that layers additional middlewares on top
+// of the parent's.
+func (g *Group) Group(middlewares ...Middleware) *Group {
+	combined := append(append([]Middleware{}, g.middlewares...), middlewares...)
+	return &Group{server: g.server, middlewares: combined}
+}
+
+// chain wraps handler with the Group's middlewares, applied outermost first.
+func (g *Group) chain(handler HandlerFunction) HandlerFunction {
+	for i := len(g.middlewares) - 1; i >= 0; i-- {
+		handler = g.middlewares[i](handler)
+	}
+	return handler
+}
+
+// HandleFunc registers handler under path with the Group's middlewares applied.
+func (g *Group) HandleFunc(path string, handler HandlerFunction, description ...string) {
+	g.server.HandleFunc(path, g.chain(handler), description...)
+}
+
+// Handle is a shortcut for HandleFunc to reply just with static text,
+// "description" is for "/help" handler.
+func (g *Group) Handle(path string, reply string, description ...string) {
+	f := func(m *Message) {
+		m.Reply(reply)
+	}
+	g.HandleFunc(path, f, description...)
+}
+
+// HandleFile adds a file handler for user uploads, scoped to the Group's
+// middlewares.
+func (g *Group) HandleFile(handler HandlerFunction, description ...string) {
+	g.server.HandleFile(g.chain(handler), description...)
+}
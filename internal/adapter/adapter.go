@@ -0,0 +1,267 @@
+// Package adapter implements the BotAdapter Server uses to talk to the
+// Telegram Bot API, or a self-hosted mirror of it.
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/sfardiansyah/tbot/model"
+)
+
+const defaultAPIEndpoint = "https://api.telegram.org"
+
+// BotAdapter is the interface Server uses to talk to Telegram: fetch
+// Updates, send Messages, and issue raw Bot API calls.
+type BotAdapter interface {
+	GetUpdatesChan(webhookURL, listenAddr string) (chan model.Update, error)
+	GetUpdates(offset, limit, timeout int, allowedUpdates []string) ([]model.Update, error)
+	Send(m *model.Message) error
+	SendRaw(endpoint string, params map[string]string) error
+	// SendFile issues endpoint as a multipart request, attaching the file at
+	// filePath under fieldName alongside params; used by setWebhook to
+	// upload a self-signed certificate.
+	SendFile(endpoint, fieldName, filePath string, params map[string]string) error
+}
+
+// bot is the default BotAdapter, backed by the Bot API (or a self-hosted
+// mirror of it) over HTTP.
+type bot struct {
+	token       string
+	httpClient  *http.Client
+	apiEndpoint string
+	localMode   bool
+}
+
+// CreateBot builds a BotAdapter for token, verifying it with a getMe call.
+// apiEndpoint points the adapter at a self-hosted Bot API server instead of
+// the default https://api.telegram.org; when empty, the default is used.
+// localMode tells the adapter the self-hosted server is running in local
+// mode, so file_path values are already local filesystem paths.
+func CreateBot(token string, httpClient *http.Client, apiEndpoint string, localMode bool) (BotAdapter, error) {
+	if apiEndpoint == "" {
+		apiEndpoint = defaultAPIEndpoint
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	b := &bot{token: token, httpClient: httpClient, apiEndpoint: apiEndpoint, localMode: localMode}
+	if _, err := b.call("getMe", nil); err != nil {
+		return nil, fmt.Errorf("adapter: getMe: %w", err)
+	}
+	return b, nil
+}
+
+func (b *bot) apiURL(method string) string {
+	return fmt.Sprintf("%s/bot%s/%s", b.apiEndpoint, b.token, method)
+}
+
+// call issues a Bot API method with form-encoded params and returns the raw
+// "result" field of the response.
+func (b *bot) call(method string, params map[string]string) (json.RawMessage, error) {
+	values := url.Values{}
+	for k, v := range params {
+		values.Set(k, v)
+	}
+
+	resp, err := b.httpClient.PostForm(b.apiURL(method), values)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("adapter: decode %s response: %w", method, err)
+	}
+	if !out.OK {
+		return nil, fmt.Errorf("adapter: %s: %s", method, out.Description)
+	}
+	return out.Result, nil
+}
+
+// GetUpdatesChan starts long-polling getUpdates against apiEndpoint and
+// streams decoded Updates on the returned channel. webhookURL/listenAddr
+// are accepted for interface symmetry with webhook-style callers but are
+// unused here: webhook delivery is owned by poller.WebhookPoller, which
+// registers itself with Telegram via SendRaw("setWebhook", ...) and runs
+// its own HTTP server instead of calling this method.
+func (b *bot) GetUpdatesChan(webhookURL, listenAddr string) (chan model.Update, error) {
+	updates := make(chan model.Update)
+	go b.pollUpdates(updates)
+	return updates, nil
+}
+
+func (b *bot) pollUpdates(dest chan<- model.Update) {
+	offset := 0
+	for {
+		updates, err := b.GetUpdates(offset, 0, 30, nil)
+		if err != nil {
+			continue
+		}
+		for _, u := range updates {
+			dest <- u
+			if u.UpdateID >= offset {
+				offset = u.UpdateID + 1
+			}
+		}
+	}
+}
+
+// GetUpdates performs a single getUpdates call tuned by offset, limit,
+// timeout, and allowedUpdates, returning the decoded Updates. It is the
+// primitive poller.LongPoller builds its loop on top of.
+func (b *bot) GetUpdates(offset, limit, timeout int, allowedUpdates []string) ([]model.Update, error) {
+	params := map[string]string{
+		"offset":  strconv.Itoa(offset),
+		"timeout": strconv.Itoa(timeout),
+	}
+	if limit > 0 {
+		params["limit"] = strconv.Itoa(limit)
+	}
+	if len(allowedUpdates) > 0 {
+		encoded, err := json.Marshal(allowedUpdates)
+		if err != nil {
+			return nil, fmt.Errorf("adapter: encode allowed_updates: %w", err)
+		}
+		params["allowed_updates"] = string(encoded)
+	}
+
+	result, err := b.call("getUpdates", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []model.Update
+	if err := json.Unmarshal(result, &updates); err != nil {
+		return nil, fmt.Errorf("adapter: decode getUpdates response: %w", err)
+	}
+	return updates, nil
+}
+
+// Send sends m to Telegram, using the Bot API method appropriate for m.Type,
+// with m.Markup, if set, as reply_markup.
+func (b *bot) Send(m *model.Message) error {
+	params := map[string]string{"chat_id": strconv.FormatInt(m.ChatID, 10)}
+	if m.Markup != nil {
+		encoded, err := json.Marshal(m.Markup)
+		if err != nil {
+			return fmt.Errorf("adapter: encode reply_markup: %w", err)
+		}
+		params["reply_markup"] = string(encoded)
+	}
+
+	method := "sendMessage"
+	switch m.Type {
+	case model.MessageText:
+		params["text"] = m.Data
+	case model.MessagePhoto:
+		method = "sendPhoto"
+		params["photo"] = m.Data
+	case model.MessageDocument:
+		method = "sendDocument"
+		params["document"] = m.Data
+	}
+
+	_, err := b.call(method, params)
+	return err
+}
+
+// SendRaw issues method directly against the Bot API with params as-is.
+func (b *bot) SendRaw(method string, params map[string]string) error {
+	_, err := b.call(method, params)
+	return err
+}
+
+// SendFile issues method as a multipart request, attaching the contents of
+// filePath under fieldName alongside params. setWebhook uses this to upload
+// a self-signed certificate's "certificate" field.
+func (b *bot) SendFile(method, fieldName, filePath string, params map[string]string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("adapter: open %s: %w", filePath, err)
+	}
+	defer f.Close()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for k, v := range params {
+		if err := w.WriteField(k, v); err != nil {
+			return fmt.Errorf("adapter: write field %s: %w", k, err)
+		}
+	}
+	part, err := w.CreateFormFile(fieldName, filepath.Base(filePath))
+	if err != nil {
+		return fmt.Errorf("adapter: create form file: %w", err)
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return fmt.Errorf("adapter: copy %s: %w", filePath, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("adapter: close multipart writer: %w", err)
+	}
+
+	resp, err := b.httpClient.Post(b.apiURL(method), w.FormDataContentType(), &body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var out struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return fmt.Errorf("adapter: decode %s response: %w", method, err)
+	}
+	if !out.OK {
+		return fmt.Errorf("adapter: %s: %s", method, out.Description)
+	}
+	return nil
+}
+
+// FilePath resolves fileID to a location the caller can read from. With the
+// default (non-local) API, this performs a getFile call and returns the
+// URL to download it from apiEndpoint. In local mode, the self-hosted
+// server already returns a local filesystem path in file_path, so no
+// download is necessary and that path is returned as-is.
+func (b *bot) FilePath(fileID string) (string, error) {
+	result, err := b.call("getFile", map[string]string{"file_id": fileID})
+	if err != nil {
+		return "", err
+	}
+
+	var out struct {
+		FilePath string `json:"file_path"`
+	}
+	if err := json.Unmarshal(result, &out); err != nil {
+		return "", fmt.Errorf("adapter: decode getFile response: %w", err)
+	}
+
+	if b.localMode {
+		return out.FilePath, nil
+	}
+	return fmt.Sprintf("%s/file/bot%s/%s", b.apiEndpoint, b.token, out.FilePath), nil
+}
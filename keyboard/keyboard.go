@@ -0,0 +1,82 @@
+// Package keyboard builds Telegram reply_markup payloads for inline and
+// reply keyboards, so handlers don't hand-roll the JSON shape.
+package keyboard
+
+// Btn is a single inline keyboard button. Tapping it sends Callback as the
+// CallbackQuery's Data field, which Server.HandleButton / HandleCallback
+// routes back to a handler.
+type Btn struct {
+	Text     string
+	Callback string
+	URL      string
+}
+
+// InlineKeyboard builds the reply_markup payload for an inline keyboard,
+// laid out as rows of Btn.
+type InlineKeyboard struct {
+	rows [][]Btn
+}
+
+// NewInlineKeyboard creates an InlineKeyboard with the given rows of Btn.
+func NewInlineKeyboard(rows ...[]Btn) *InlineKeyboard {
+	return &InlineKeyboard{rows: rows}
+}
+
+// Row appends a row of buttons to the keyboard and returns it for chaining.
+func (k *InlineKeyboard) Row(buttons ...Btn) *InlineKeyboard {
+	k.rows = append(k.rows, buttons)
+	return k
+}
+
+// Markup returns the Telegram reply_markup representation of the keyboard.
+func (k *InlineKeyboard) Markup() map[string]interface{} {
+	rows := make([][]map[string]string, len(k.rows))
+	for i, row := range k.rows {
+		buttons := make([]map[string]string, len(row))
+		for j, b := range row {
+			btn := map[string]string{"text": b.Text}
+			if b.Callback != "" {
+				btn["callback_data"] = b.Callback
+			}
+			if b.URL != "" {
+				btn["url"] = b.URL
+			}
+			buttons[j] = btn
+		}
+		rows[i] = buttons
+	}
+	return map[string]interface{}{"inline_keyboard": rows}
+}
+
+// ReplyKeyboard builds the reply_markup payload for a custom reply keyboard.
+type ReplyKeyboard struct {
+	rows    [][]string
+	resize  bool
+	oneTime bool
+}
+
+// NewReplyKeyboard creates a ReplyKeyboard with the given rows of button text.
+func NewReplyKeyboard(rows ...[]string) *ReplyKeyboard {
+	return &ReplyKeyboard{rows: rows}
+}
+
+// Resize marks the keyboard as resizable and returns it for chaining.
+func (k *ReplyKeyboard) Resize() *ReplyKeyboard {
+	k.resize = true
+	return k
+}
+
+// OneTime hides the keyboard after one use and returns it for chaining.
+func (k *ReplyKeyboard) OneTime() *ReplyKeyboard {
+	k.oneTime = true
+	return k
+}
+
+// Markup returns the Telegram reply_markup representation of the keyboard.
+func (k *ReplyKeyboard) Markup() map[string]interface{} {
+	return map[string]interface{}{
+		"keyboard":          k.rows,
+		"resize_keyboard":   k.resize,
+		"one_time_keyboard": k.oneTime,
+	}
+}
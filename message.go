@@ -0,0 +1,20 @@
+package tbot
+
+import (
+	"github.com/sfardiansyah/tbot/internal/adapter"
+	"github.com/sfardiansyah/tbot/model"
+)
+
+// Message wraps an incoming Update with the Bot needed to reply to it.
+type Message struct {
+	Message model.Update
+	bot     adapter.BotAdapter
+}
+
+// Reply sends text back to the chat the Message came from.
+func (m *Message) Reply(text string) {
+	if m.bot == nil || m.Message.Chat == nil {
+		return
+	}
+	_ = m.bot.Send(&model.Message{Type: model.MessageText, ChatID: m.Message.Chat.ID, Data: text})
+}
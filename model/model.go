@@ -0,0 +1,80 @@
+// Package model defines the data types exchanged between tbot and the
+// Telegram Bot API: outgoing Messages and incoming Updates.
+package model
+
+// MessageType identifies what kind of payload Message.Data carries when
+// sending a message.
+type MessageType int
+
+const (
+	// MessageText is a plain text message.
+	MessageText MessageType = iota
+	// MessagePhoto is a photo; Data holds a file ID, URL, or local path.
+	MessagePhoto
+	// MessageDocument is a document; Data holds a file ID, URL, or local path.
+	MessageDocument
+)
+
+// Message is an outgoing message to be sent to a chat.
+type Message struct {
+	Type   MessageType
+	ChatID int64
+	Data   string
+	Markup map[string]interface{}
+}
+
+// User is a Telegram user or bot.
+type User struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// Chat is a Telegram chat: private, group, supergroup, or channel.
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// CallbackQuery is sent when a user taps an inline keyboard button. Chat is
+// the chat the tapped message belongs to, so a callback handler can reply
+// without a separate message Update.
+type CallbackQuery struct {
+	ID   string
+	From *User
+	Data string
+	Chat *Chat
+}
+
+// InlineQuery is sent when a user types "@botusername query" in any chat.
+type InlineQuery struct {
+	ID    string `json:"id"`
+	From  *User  `json:"from"`
+	Query string `json:"query"`
+}
+
+// ChatMemberUpdated represents a change in a chat member's status.
+type ChatMemberUpdated struct {
+	Chat *Chat `json:"chat"`
+	From *User `json:"from"`
+}
+
+// Update is a single incoming update from Telegram, as documented at
+// https://core.telegram.org/bots/api#update, flattened for convenience: a
+// plain message's text/sender/chat are exposed directly as Data/From/Chat,
+// an edited message or channel post is carried the same way in the nested
+// EditedMessage/ChannelPost Update, and the other fields identify which
+// kind of update this is.
+type Update struct {
+	UpdateID      int
+	Data          string
+	From          *User
+	Chat          *Chat
+	CallbackQuery *CallbackQuery
+	InlineQuery   *InlineQuery
+	EditedMessage *Update
+	ChannelPost   *Update
+	ChatMember    *ChatMemberUpdated
+	MyChatMember  *ChatMemberUpdated
+}
@@ -0,0 +1,66 @@
+package model
+
+import "encoding/json"
+
+// rawMessage mirrors the JSON shape of a Telegram Message object.
+type rawMessage struct {
+	MessageID int    `json:"message_id"`
+	From      *User  `json:"from"`
+	Chat      *Chat  `json:"chat"`
+	Text      string `json:"text"`
+}
+
+// rawCallbackQuery mirrors the JSON shape of a Telegram CallbackQuery
+// object; Message is the message the tapped button was attached to, which
+// carries the Chat a reply needs.
+type rawCallbackQuery struct {
+	ID      string      `json:"id"`
+	From    *User       `json:"from"`
+	Data    string      `json:"data"`
+	Message *rawMessage `json:"message"`
+}
+
+// rawUpdate mirrors https://core.telegram.org/bots/api#update for JSON
+// decoding; UnmarshalJSON flattens it into Update.
+type rawUpdate struct {
+	UpdateID      int                `json:"update_id"`
+	Message       *rawMessage        `json:"message"`
+	EditedMessage *rawMessage        `json:"edited_message"`
+	ChannelPost   *rawMessage        `json:"channel_post"`
+	CallbackQuery *rawCallbackQuery  `json:"callback_query"`
+	InlineQuery   *InlineQuery       `json:"inline_query"`
+	ChatMember    *ChatMemberUpdated `json:"chat_member"`
+	MyChatMember  *ChatMemberUpdated `json:"my_chat_member"`
+}
+
+// UnmarshalJSON decodes a Telegram Update payload, as received from
+// getUpdates or a webhook POST, into its flattened Update representation.
+func (u *Update) UnmarshalJSON(data []byte) error {
+	var raw rawUpdate
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*u = Update{
+		UpdateID:     raw.UpdateID,
+		InlineQuery:  raw.InlineQuery,
+		ChatMember:   raw.ChatMember,
+		MyChatMember: raw.MyChatMember,
+	}
+	if raw.CallbackQuery != nil {
+		u.CallbackQuery = &CallbackQuery{ID: raw.CallbackQuery.ID, From: raw.CallbackQuery.From, Data: raw.CallbackQuery.Data}
+		if raw.CallbackQuery.Message != nil {
+			u.CallbackQuery.Chat = raw.CallbackQuery.Message.Chat
+		}
+	}
+	if raw.Message != nil {
+		u.Data, u.From, u.Chat = raw.Message.Text, raw.Message.From, raw.Message.Chat
+	}
+	if raw.EditedMessage != nil {
+		u.EditedMessage = &Update{Data: raw.EditedMessage.Text, From: raw.EditedMessage.From, Chat: raw.EditedMessage.Chat}
+	}
+	if raw.ChannelPost != nil {
+		u.ChannelPost = &Update{Data: raw.ChannelPost.Text, From: raw.ChannelPost.From, Chat: raw.ChannelPost.Chat}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package tbot
+
+import "strings"
+
+// HandlerFunction handles a single incoming Message.
+type HandlerFunction func(*Message)
+
+// route is a single registered command and its /help description.
+type route struct {
+	handler     HandlerFunction
+	description string
+}
+
+// Mux routes incoming Messages to registered command handlers, the way
+// http.ServeMux routes requests to handlers by path; WithMux lets a Server
+// use a custom implementation.
+type Mux interface {
+	HandleFunc(path string, handler HandlerFunction, description ...string)
+	HandleFile(handler HandlerFunction, description ...string)
+	HandleDefault(handler HandlerFunction, description ...string)
+	SetAlias(path string, aliases ...string)
+	Reset(chatID int64)
+
+	// ServeMessage dispatches m to whichever handler matches its command
+	// text, falling back to the default handler when none matches; a
+	// message with no text goes to the file handler.
+	ServeMessage(m *Message)
+}
+
+// defaultMux is the Mux installed on every Server unless overridden with
+// WithMux.
+type defaultMux struct {
+	routes         map[string]route
+	aliases        map[string]string
+	fileHandler    HandlerFunction
+	defaultHandler HandlerFunction
+}
+
+// NewDefaultMux creates the Mux used by NewServer unless WithMux overrides it.
+func NewDefaultMux() Mux {
+	return &defaultMux{
+		routes:  make(map[string]route),
+		aliases: make(map[string]string),
+	}
+}
+
+func (d *defaultMux) HandleFunc(path string, handler HandlerFunction, description ...string) {
+	desc := ""
+	if len(description) > 0 {
+		desc = description[0]
+	}
+	d.routes[path] = route{handler: handler, description: desc}
+}
+
+func (d *defaultMux) HandleFile(handler HandlerFunction, description ...string) {
+	d.fileHandler = handler
+}
+
+func (d *defaultMux) HandleDefault(handler HandlerFunction, description ...string) {
+	d.defaultHandler = handler
+}
+
+func (d *defaultMux) SetAlias(path string, aliases ...string) {
+	for _, alias := range aliases {
+		d.aliases[alias] = path
+	}
+}
+
+func (d *defaultMux) Reset(chatID int64) {}
+
+// ServeMessage implements Mux.
+func (d *defaultMux) ServeMessage(m *Message) {
+	text := m.Message.Data
+	if text == "" {
+		if d.fileHandler != nil {
+			d.fileHandler(m)
+		}
+		return
+	}
+
+	command := strings.Fields(text)[0]
+	if target, ok := d.aliases[command]; ok {
+		command = target
+	}
+
+	if r, ok := d.routes[command]; ok {
+		r.handler(m)
+		return
+	}
+	if d.defaultHandler != nil {
+		d.defaultHandler(m)
+	}
+}
+
+// HelpHandler replies with every route registered via HandleFunc that has a
+// description, one per line. It is registered as the default "/help"
+// handler by NewServer.
+func (s *Server) HelpHandler(m *Message) {
+	dm, ok := s.mux.(*defaultMux)
+	if !ok {
+		m.Reply("No help available.")
+		return
+	}
+
+	var b strings.Builder
+	for path, r := range dm.routes {
+		if r.description == "" {
+			continue
+		}
+		b.WriteString(path)
+		b.WriteString(" - ")
+		b.WriteString(r.description)
+		b.WriteString("\n")
+	}
+	if b.Len() == 0 {
+		m.Reply("No commands available.")
+		return
+	}
+	m.Reply(b.String())
+}
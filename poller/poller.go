@@ -0,0 +1,230 @@
+// Package poller provides pluggable strategies for feeding Telegram Updates
+// into a tbot Server: long-polling, webhook, and wrappers that layer
+// filtering or deduplication on top of another Poller.
+package poller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/sfardiansyah/tbot/internal/adapter"
+	"github.com/sfardiansyah/tbot/model"
+)
+
+// Poller fetches Updates from Telegram (or another source) and writes them
+// to dest until stop is closed or ctx is cancelled.
+type Poller interface {
+	Poll(ctx context.Context, bot adapter.BotAdapter, dest chan<- model.Update, stop <-chan struct{}) error
+}
+
+// LongPoller repeatedly calls getUpdates, tuned by Timeout/Limit/AllowedUpdates.
+type LongPoller struct {
+	// Timeout is the long-polling timeout, in seconds, sent to getUpdates.
+	Timeout int
+	// Limit caps how many Updates are requested per call.
+	Limit int
+	// AllowedUpdates restricts which update kinds Telegram will deliver.
+	AllowedUpdates []string
+	// LastUpdateID resumes polling after the Update with this ID.
+	LastUpdateID int
+}
+
+// Poll implements Poller.
+func (p *LongPoller) Poll(ctx context.Context, bot adapter.BotAdapter, dest chan<- model.Update, stop <-chan struct{}) error {
+	offset := p.LastUpdateID
+	if offset > 0 {
+		offset++
+	}
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = 30
+	}
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		updates, err := bot.GetUpdates(offset, p.Limit, timeout, p.AllowedUpdates)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range updates {
+			select {
+			case <-stop:
+				return nil
+			case dest <- u:
+			}
+			if u.UpdateID >= offset {
+				offset = u.UpdateID + 1
+			}
+		}
+	}
+}
+
+// WebhookPoller runs an HTTP(S) server and feeds it Updates Telegram pushes.
+type WebhookPoller struct {
+	// Listen is the local address to listen on, e.g. "0.0.0.0:8080".
+	Listen string
+	// URL is the public HTTPS URL registered with setWebhook.
+	URL string
+	// MaxConnections caps simultaneous HTTPS connections Telegram may open.
+	MaxConnections int
+	// SecretToken is checked against X-Telegram-Bot-Api-Secret-Token.
+	SecretToken string
+	// DropPendingUpdates discards any Updates queued before the webhook is set.
+	DropPendingUpdates bool
+	// CertFile and KeyFile, if both set, serve the webhook over HTTPS.
+	CertFile string
+	KeyFile  string
+	// AllowedUpdates restricts which update kinds Telegram will deliver.
+	AllowedUpdates []string
+	// Mux, if set, gets the webhook handler mounted on it and is served on
+	// Listen in place of a fresh http.ServeMux, so other routes on Mux
+	// share the webhook's listener.
+	Mux *http.ServeMux
+}
+
+// Poll implements Poller. It registers the webhook with Telegram via
+// setWebhook, forwarding SecretToken/MaxConnections/DropPendingUpdates/
+// AllowedUpdates, then runs an HTTP(S) server on Listen (serving Mux, if
+// set, with the webhook handler mounted on it) to receive pushed Updates.
+// When SecretToken is set, every request must present it in
+// X-Telegram-Bot-Api-Secret-Token, or it is rejected with 401.
+func (p *WebhookPoller) Poll(ctx context.Context, bot adapter.BotAdapter, dest chan<- model.Update, stop <-chan struct{}) error {
+	if err := p.registerWebhook(bot); err != nil {
+		return err
+	}
+
+	mux := p.Mux
+	if mux == nil {
+		mux = http.NewServeMux()
+	}
+	mux.HandleFunc(p.path(), p.handler(dest))
+
+	server := &http.Server{Addr: p.Listen, Handler: mux}
+
+	errc := make(chan error, 1)
+	go func() {
+		var err error
+		if p.CertFile != "" && p.KeyFile != "" {
+			err = server.ListenAndServeTLS(p.CertFile, p.KeyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err == http.ErrServerClosed {
+			err = nil
+		}
+		errc <- err
+	}()
+
+	select {
+	case <-stop:
+		return server.Close()
+	case <-ctx.Done():
+		server.Close()
+		return ctx.Err()
+	case err := <-errc:
+		return err
+	}
+}
+
+// handler returns the http.HandlerFunc that decodes a pushed Update,
+// checking SecretToken before accepting it.
+func (p *WebhookPoller) handler(dest chan<- model.Update) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if p.SecretToken != "" && r.Header.Get("X-Telegram-Bot-Api-Secret-Token") != p.SecretToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var update model.Update
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		dest <- update
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// path returns the URL path Telegram will POST updates to, derived from URL.
+func (p *WebhookPoller) path() string {
+	u, err := url.Parse(p.URL)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+// registerWebhook calls setWebhook with URL plus every security/tuning
+// option configured on p. If CertFile is set, it is uploaded as the
+// "certificate" field, which Telegram requires for a self-signed cert.
+func (p *WebhookPoller) registerWebhook(bot adapter.BotAdapter) error {
+	params := map[string]string{"url": p.URL}
+	if p.SecretToken != "" {
+		params["secret_token"] = p.SecretToken
+	}
+	if p.MaxConnections > 0 {
+		params["max_connections"] = strconv.Itoa(p.MaxConnections)
+	}
+	if p.DropPendingUpdates {
+		params["drop_pending_updates"] = "true"
+	}
+	if len(p.AllowedUpdates) > 0 {
+		encoded, err := json.Marshal(p.AllowedUpdates)
+		if err != nil {
+			return fmt.Errorf("poller: encode allowed_updates: %w", err)
+		}
+		params["allowed_updates"] = string(encoded)
+	}
+	if p.CertFile != "" {
+		return bot.SendFile("setWebhook", "certificate", p.CertFile, params)
+	}
+	return bot.SendRaw("setWebhook", params)
+}
+
+// MiddlewarePoller wraps another Poller to filter, sample, or deduplicate
+// the Updates it produces before they reach dest.
+type MiddlewarePoller struct {
+	// Poller is the wrapped source of Updates.
+	Poller Poller
+	// Capacity sizes the internal buffering channel.
+	Capacity int
+	// Filter decides whether an Update is forwarded; nil forwards all.
+	Filter func(*model.Update) bool
+}
+
+// Poll implements Poller.
+func (p *MiddlewarePoller) Poll(ctx context.Context, bot adapter.BotAdapter, dest chan<- model.Update, stop <-chan struct{}) error {
+	buf := make(chan model.Update, p.Capacity)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- p.Poller.Poll(ctx, bot, buf, stop)
+	}()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case err := <-errc:
+			return err
+		case u, ok := <-buf:
+			if !ok {
+				return nil
+			}
+			if p.Filter == nil || p.Filter(&u) {
+				dest <- u
+			}
+		}
+	}
+}
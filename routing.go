@@ -0,0 +1,76 @@
+package tbot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sfardiansyah/tbot/keyboard"
+)
+
+// ContextHandlerFunction is the Context-based handler signature used by the
+// routing methods below (HandleCallback, HandleInlineQuery, ...).
+type ContextHandlerFunction func(Context) error
+
+type callbackRoute struct {
+	prefix  bool
+	pattern string
+	handler ContextHandlerFunction
+}
+
+// HandleCallback registers handler for CallbackQuery updates whose Data
+// matches pattern exactly, or starts with pattern when pattern ends in "*"
+// (e.g. "btn:*" matches any callback_data starting with "btn:"). Routes are
+// tried in registration order and the first match wins.
+func (s *Server) HandleCallback(pattern string, handler ContextHandlerFunction) {
+	route := callbackRoute{pattern: pattern, handler: handler}
+	if strings.HasSuffix(pattern, "*") {
+		route.prefix = true
+		route.pattern = strings.TrimSuffix(pattern, "*")
+	}
+	s.callbackRoutes = append(s.callbackRoutes, route)
+}
+
+// HandleButton wires btn to handler as a callback route, filling in
+// btn.Callback with a generated identifier if it is empty so the keyboard
+// and its handler stay in sync without hand-picking callback_data.
+func (s *Server) HandleButton(btn *keyboard.Btn, handler ContextHandlerFunction) {
+	if btn.Callback == "" {
+		s.btnSeq++
+		btn.Callback = fmt.Sprintf("btn:%d", s.btnSeq)
+	}
+	s.HandleCallback(btn.Callback, handler)
+}
+
+// HandleInlineQuery registers handler for InlineQuery updates.
+func (s *Server) HandleInlineQuery(handler ContextHandlerFunction) {
+	s.inlineQueryHandler = handler
+}
+
+// HandleEdited registers handler for edited messages.
+func (s *Server) HandleEdited(handler ContextHandlerFunction) {
+	s.editedHandler = handler
+}
+
+// HandleChannelPost registers handler for channel post updates.
+func (s *Server) HandleChannelPost(handler ContextHandlerFunction) {
+	s.channelPostHandler = handler
+}
+
+// HandleChatMember registers handler for chat member updates (users joining
+// or leaving a chat the bot administers).
+func (s *Server) HandleChatMember(handler ContextHandlerFunction) {
+	s.chatMemberHandler = handler
+}
+
+// matchCallback returns the handler registered for callback data, or nil.
+func (s *Server) matchCallback(data string) ContextHandlerFunction {
+	for _, route := range s.callbackRoutes {
+		if route.prefix && strings.HasPrefix(data, route.pattern) {
+			return route.handler
+		}
+		if !route.prefix && data == route.pattern {
+			return route.handler
+		}
+	}
+	return nil
+}
@@ -1,10 +1,12 @@
 package tbot
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/sfardiansyah/tbot/internal/adapter"
 	"github.com/sfardiansyah/tbot/model"
+	"github.com/sfardiansyah/tbot/poller"
 )
 
 // Server is a telegram bot server. Looks and feels like net/http.
@@ -15,14 +17,37 @@ type Server struct {
 	webhookURL  string
 	listenAddr  string
 	bot         adapter.BotAdapter
+	poller      poller.Poller
+	stopCh      chan struct{}
+	apiEndpoint string
+	localMode   bool
+
+	callbackRoutes     []callbackRoute
+	btnSeq             int
+	inlineQueryHandler ContextHandlerFunction
+	editedHandler      ContextHandlerFunction
+	channelPostHandler ContextHandlerFunction
+	chatMemberHandler  ContextHandlerFunction
+
+	onError func(err error, c Context)
+
+	offline bool
+
+	webhookCertFile           string
+	webhookKeyFile            string
+	webhookSecretToken        string
+	webhookAllowedUpdates     []string
+	webhookMux                *http.ServeMux
+	webhookMaxConnections     int
+	webhookDropPendingUpdates bool
 }
 
 // Middleware function takes HandlerFunction and returns HandlerFunction.
 // Should call it's argument function inside, if needed.
 type Middleware func(HandlerFunction) HandlerFunction
 
-var createBot = func(token string, httpClient *http.Client) (adapter.BotAdapter, error) {
-	return adapter.CreateBot(token, httpClient)
+var createBot = func(token string, httpClient *http.Client, apiEndpoint string, localMode bool) (adapter.BotAdapter, error) {
+	return adapter.CreateBot(token, httpClient, apiEndpoint, localMode)
 }
 
 // ServerOption is a functional option for Server
@@ -30,6 +55,9 @@ type ServerOption func(*Server)
 
 // WithWebhook returns ServerOption for given Webhook URL and Server address to listen.
 // e.g. WithWebook("https://bot.example.com/super/url", "0.0.0.0:8080")
+//
+// Deprecated: use WithPoller with a *poller.WebhookPoller for control over
+// TLS, secret-token validation, and listener sharing.
 func WithWebhook(url string, addr string) ServerOption {
 	return func(s *Server) {
 		s.webhookURL = url
@@ -37,6 +65,69 @@ func WithWebhook(url string, addr string) ServerOption {
 	}
 }
 
+// WithPoller sets the Poller used to fetch Updates, replacing the default
+// behaviour driven by WithWebhook.
+func WithPoller(p poller.Poller) ServerOption {
+	return func(s *Server) {
+		s.poller = p
+	}
+}
+
+// WithWebhookTLS serves the webhook over HTTPS using certFile/keyFile,
+// instead of plain HTTP. Use this with a self-signed certificate registered
+// directly with Telegram via setWebhook's "certificate" field.
+func WithWebhookTLS(certFile, keyFile string) ServerOption {
+	return func(s *Server) {
+		s.webhookCertFile = certFile
+		s.webhookKeyFile = keyFile
+	}
+}
+
+// WithWebhookSecretToken sets the secret token Telegram must present in the
+// X-Telegram-Bot-Api-Secret-Token header on every webhook request; requests
+// with a missing or mismatched header are rejected with 401.
+func WithWebhookSecretToken(token string) ServerOption {
+	return func(s *Server) {
+		s.webhookSecretToken = token
+	}
+}
+
+// WithWebhookAllowedUpdates restricts which update kinds Telegram delivers
+// to the webhook, forwarded as-is to setWebhook.
+func WithWebhookAllowedUpdates(updates []string) ServerOption {
+	return func(s *Server) {
+		s.webhookAllowedUpdates = updates
+	}
+}
+
+// WithWebhookMaxConnections caps the number of simultaneous HTTPS
+// connections Telegram may open to deliver webhook updates, forwarded as-is
+// to setWebhook's max_connections field.
+func WithWebhookMaxConnections(n int) ServerOption {
+	return func(s *Server) {
+		s.webhookMaxConnections = n
+	}
+}
+
+// WithWebhookDropPendingUpdates discards any Updates Telegram queued before
+// the webhook is (re)registered, forwarded as-is to setWebhook's
+// drop_pending_updates field.
+func WithWebhookDropPendingUpdates(drop bool) ServerOption {
+	return func(s *Server) {
+		s.webhookDropPendingUpdates = drop
+	}
+}
+
+// WithWebhookMux mounts the webhook handler on mux and serves mux on the
+// webhook's listen address in place of a fresh one, so the bot webhook
+// shares a listener with other routes on mux such as health checks or
+// metrics.
+func WithWebhookMux(mux *http.ServeMux) ServerOption {
+	return func(s *Server) {
+		s.webhookMux = mux
+	}
+}
+
 // WithMux sets custom mux for server. Should satisfy Mux interface.
 func WithMux(m Mux) ServerOption {
 	return func(s *Server) {
@@ -51,23 +142,46 @@ func WithHttpClient(client *http.Client) ServerOption {
 	}
 }
 
+// WithAPIEndpoint points the Server at a self-hosted Bot API server instead
+// of the default https://api.telegram.org, e.g. for local-mode file access
+// or uploads larger than the public API's 50 MB limit.
+func WithAPIEndpoint(url string) ServerOption {
+	return func(s *Server) {
+		s.apiEndpoint = url
+	}
+}
+
+// WithLocalMode tells the adapter it is talking to a self-hosted Bot API
+// server running in local mode, so file_path values in responses are
+// already local filesystem paths and should not be downloaded.
+func WithLocalMode(local bool) ServerOption {
+	return func(s *Server) {
+		s.localMode = local
+	}
+}
+
 // NewServer creates new Server with Telegram API Token
 // and default /help handler using go default http client
 func NewServer(token string, options ...ServerOption) (*Server, error) {
 	server := &Server{
 		mux:        NewDefaultMux(),
 		httpClient: http.DefaultClient,
+		onError:    defaultOnError,
 	}
 
 	for _, option := range options {
 		option(server)
 	}
 
-	tbot, err := createBot(token, server.httpClient)
-	if err != nil {
-		return nil, err
+	if server.offline {
+		server.bot = newOfflineAdapter()
+	} else {
+		tbot, err := createBot(token, server.httpClient, server.apiEndpoint, server.localMode)
+		if err != nil {
+			return nil, err
+		}
+		server.bot = tbot
 	}
-	server.bot = tbot
 
 	server.HandleFunc("/help", server.HelpHandler)
 
@@ -81,13 +195,69 @@ func (s *Server) AddMiddleware(mid Middleware) {
 
 // ListenAndServe starts Server, returns error on failure
 func (s *Server) ListenAndServe() error {
+	s.stopCh = make(chan struct{})
+
+	if s.poller == nil && s.webhookURL != "" {
+		s.poller = &poller.WebhookPoller{
+			Listen:             s.listenAddr,
+			URL:                s.webhookURL,
+			CertFile:           s.webhookCertFile,
+			KeyFile:            s.webhookKeyFile,
+			SecretToken:        s.webhookSecretToken,
+			AllowedUpdates:     s.webhookAllowedUpdates,
+			Mux:                s.webhookMux,
+			MaxConnections:     s.webhookMaxConnections,
+			DropPendingUpdates: s.webhookDropPendingUpdates,
+		}
+	}
+
+	if s.poller != nil {
+		dest := make(chan model.Update)
+		errc := make(chan error, 1)
+		go func() {
+			errc <- s.poller.Poll(context.Background(), s.bot, dest, s.stopCh)
+		}()
+		for {
+			select {
+			case err := <-errc:
+				return err
+			case update, ok := <-dest:
+				if !ok {
+					return nil
+				}
+				go s.safeProcessMessage(&Message{Message: update})
+			}
+		}
+	}
+
 	updates, err := s.bot.GetUpdatesChan(s.webhookURL, s.listenAddr)
 	if err != nil {
 		return err
 	}
-	for update := range updates {
-		go s.processMessage(&Message{Message: update})
+	for {
+		select {
+		case <-s.stopCh:
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			go s.safeProcessMessage(&Message{Message: update})
+		}
+	}
+}
+
+// Stop signals ListenAndServe to return, without releasing the Bot itself.
+func (s *Server) Stop() {
+	if s.stopCh != nil {
+		close(s.stopCh)
 	}
+}
+
+// Close stops the Server. It currently just calls Stop, but is provided
+// for symmetry with other Close-able resources and for future cleanup.
+func (s *Server) Close() error {
+	s.Stop()
 	return nil
 }
 